@@ -0,0 +1,51 @@
+// Package auth provides pluggable request authentication for a SCIM service provider, as required by RFC 7644 §2.
+package auth
+
+import "net/http"
+
+// Principal identifies the caller an Authenticator has authenticated a request as. The server places it on the
+// request context with WithPrincipal so that ResourceHandler implementations can use it for tenant scoping.
+type Principal struct {
+	// ID is the stable identifier of the caller, e.g. a JWT "sub" claim, a static token's associated client name,
+	// or a certificate's subject CN/URI-SAN.
+	ID string
+	// Scheme is the authentication scheme that produced this Principal, e.g. "Bearer" or "mTLS".
+	Scheme string
+}
+
+// Authenticator authenticates an incoming HTTP request.
+type Authenticator interface {
+	// Authenticate returns the Principal the request authenticates as, or an error if the request is not
+	// authenticated. The error message is never exposed to the client, which always receives a 401 SCIM error;
+	// authenticationError, if the error is one, supplies the WWW-Authenticate challenge sent with it.
+	Authenticate(r *http.Request) (Principal, error)
+	// Scheme identifies this authenticator for ServiceProviderConfig.AuthenticationSchemes, e.g. "Bearer" or
+	// "mTLS".
+	Scheme() string
+}
+
+// authenticationError carries a detail message and the WWW-Authenticate challenge to present to the client for a
+// failed authentication attempt.
+type authenticationError struct {
+	detail    string
+	challenge string
+}
+
+func (e authenticationError) Error() string { return e.detail }
+
+// errMissingCredentials is returned by an Authenticator when the request carries no credentials at all.
+func errMissingCredentials(challenge string) error {
+	return authenticationError{detail: "no credentials supplied", challenge: challenge}
+}
+
+// errInvalidCredentials is returned by an Authenticator when the request's credentials were rejected.
+func errInvalidCredentials(challenge string) error {
+	return authenticationError{detail: "invalid credentials", challenge: challenge}
+}
+
+// Challenge returns the WWW-Authenticate challenge to send back for err, if err was produced by this package, and
+// whether one was found.
+func Challenge(err error) (string, bool) {
+	authErr, ok := err.(authenticationError)
+	return authErr.challenge, ok
+}
@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// BearerJWT authenticates requests whose bearer token is an RS256 or ES256 JWT, verified against the signing keys
+// published at a JWKS URL. Keys are cached and refreshed on a TTL to avoid fetching the JWKS on every request.
+type BearerJWT struct {
+	JWKSURL  string
+	Issuer   string
+	Audience string
+	// PrincipalClaim is the claim used as Principal.ID, defaulting to "sub".
+	PrincipalClaim string
+	// CacheTTL controls how long fetched JWKS keys are cached before being refreshed. Defaults to 10 minutes.
+	CacheTTL time.Duration
+
+	httpClient *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+}
+
+// NewBearerJWT returns a BearerJWT validating tokens issued by issuer for audience, whose signing keys are fetched
+// from jwksURL.
+func NewBearerJWT(jwksURL, issuer, audience string) *BearerJWT {
+	return &BearerJWT{
+		JWKSURL:        jwksURL,
+		Issuer:         issuer,
+		Audience:       audience,
+		PrincipalClaim: "sub",
+		CacheTTL:       10 * time.Minute,
+		httpClient:     http.DefaultClient,
+	}
+}
+
+// Scheme implements Authenticator.
+func (b *BearerJWT) Scheme() string { return "Bearer" }
+
+// Authenticate implements Authenticator.
+func (b *BearerJWT) Authenticate(r *http.Request) (Principal, error) {
+	raw, ok := bearerToken(r)
+	if !ok {
+		return Principal{}, errMissingCredentials(`Bearer realm="scim"`)
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, b.keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "ES256"}),
+		jwt.WithIssuer(b.Issuer),
+		jwt.WithAudience(b.Audience),
+	)
+	if err != nil {
+		return Principal{}, errInvalidCredentials(`Bearer realm="scim", error="invalid_token"`)
+	}
+
+	id, _ := claims[b.PrincipalClaim].(string)
+	if id == "" {
+		return Principal{}, errInvalidCredentials(`Bearer realm="scim", error="invalid_token"`)
+	}
+	return Principal{ID: id, Scheme: "Bearer"}, nil
+}
+
+// keyFunc resolves the signing key for a token's "kid" header against the cached JWKS, refreshing it first if it
+// has expired.
+func (b *BearerJWT) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	keys, err := b.currentKeys()
+	if err != nil {
+		return nil, err
+	}
+	if key, ok := keys[kid]; ok {
+		return key, nil
+	}
+
+	// The key may have rotated in since our last fetch; refresh once before giving up.
+	keys, err = b.refreshKeys()
+	if err != nil {
+		return nil, err
+	}
+	key, ok := keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("auth: unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (b *BearerJWT) currentKeys() (map[string]interface{}, error) {
+	b.mu.RLock()
+	if b.keys != nil && time.Since(b.fetchedAt) < b.CacheTTL {
+		defer b.mu.RUnlock()
+		return b.keys, nil
+	}
+	b.mu.RUnlock()
+	return b.refreshKeys()
+}
+
+// jwks is the minimal shape of a JSON Web Key Set response needed to extract RSA and EC public keys.
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Crv string `json:"crv"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+		X   string `json:"x"`
+		Y   string `json:"y"`
+	} `json:"keys"`
+}
+
+func (b *BearerJWT) refreshKeys() (map[string]interface{}, error) {
+	resp, err := b.httpClient.Get(b.JWKSURL)
+	if err != nil {
+		return nil, fmt.Errorf("auth: fetching JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var set jwks
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("auth: decoding JWKS: %w", err)
+	}
+
+	keys := make(map[string]interface{}, len(set.Keys))
+	for _, k := range set.Keys {
+		switch k.Kty {
+		case "RSA":
+			key, err := rsaPublicKey(k.N, k.E)
+			if err != nil {
+				continue
+			}
+			keys[k.Kid] = key
+		case "EC":
+			key, err := ecdsaPublicKey(k.Crv, k.X, k.Y)
+			if err != nil {
+				continue
+			}
+			keys[k.Kid] = key
+		}
+	}
+
+	b.mu.Lock()
+	b.keys = keys
+	b.fetchedAt = time.Now()
+	b.mu.Unlock()
+	return keys, nil
+}
+
+func rsaPublicKey(n, e string) (*rsa.PublicKey, error) {
+	nBytes, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	eBytes, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(nBytes),
+		E: int(new(big.Int).SetBytes(eBytes).Int64()),
+	}, nil
+}
+
+func ecdsaPublicKey(crv, x, y string) (*ecdsa.PublicKey, error) {
+	xBytes, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, err
+	}
+	yBytes, err := base64.RawURLEncoding.DecodeString(y)
+	if err != nil {
+		return nil, err
+	}
+
+	var curve elliptic.Curve
+	switch crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("auth: unsupported EC curve %q", crv)
+	}
+
+	return &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(xBytes),
+		Y:     new(big.Int).SetBytes(yBytes),
+	}, nil
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// BearerStatic authenticates requests against a rotating set of accepted bearer tokens, so that tokens can be
+// rolled without rejecting requests mid-rotation.
+type BearerStatic struct {
+	mu     sync.RWMutex
+	tokens map[string]Principal
+}
+
+// NewBearerStatic returns a BearerStatic accepting the given tokens, each mapped to the Principal it authenticates
+// as.
+func NewBearerStatic(tokens map[string]Principal) *BearerStatic {
+	b := &BearerStatic{tokens: make(map[string]Principal, len(tokens))}
+	for token, principal := range tokens {
+		b.tokens[token] = principal
+	}
+	return b
+}
+
+// SetTokens atomically replaces the accepted set of tokens, e.g. to add a newly issued token and retire an old one
+// in the same rotation.
+func (b *BearerStatic) SetTokens(tokens map[string]Principal) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = make(map[string]Principal, len(tokens))
+	for token, principal := range tokens {
+		b.tokens[token] = principal
+	}
+}
+
+// Scheme implements Authenticator.
+func (b *BearerStatic) Scheme() string { return "Bearer" }
+
+// Authenticate implements Authenticator.
+func (b *BearerStatic) Authenticate(r *http.Request) (Principal, error) {
+	token, ok := bearerToken(r)
+	if !ok {
+		return Principal{}, errMissingCredentials(`Bearer realm="scim"`)
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for candidate, principal := range b.tokens {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(token)) == 1 {
+			return principal, nil
+		}
+	}
+	return Principal{}, errInvalidCredentials(`Bearer realm="scim", error="invalid_token"`)
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header.
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(header, prefix), true
+}
@@ -0,0 +1,17 @@
+package auth
+
+import "context"
+
+type principalContextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying principal, so that ResourceHandler implementations further down the
+// call chain can retrieve it with FromContext for tenant scoping.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// FromContext returns the Principal a request was authenticated as, if any.
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	return principal, ok
+}
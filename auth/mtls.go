@@ -0,0 +1,26 @@
+package auth
+
+import "net/http"
+
+// MTLS authenticates requests by their verified client TLS certificate, requiring net/http's TLS termination to
+// have already validated the chain (tls.Config.ClientAuth set to RequireAndVerifyClientCert or similar).
+type MTLS struct{}
+
+// Scheme implements Authenticator.
+func (MTLS) Scheme() string { return "mTLS" }
+
+// Authenticate implements Authenticator.
+func (MTLS) Authenticate(r *http.Request) (Principal, error) {
+	if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+		return Principal{}, errMissingCredentials("")
+	}
+
+	leaf := r.TLS.VerifiedChains[0][0]
+	if len(leaf.URIs) > 0 {
+		return Principal{ID: leaf.URIs[0].String(), Scheme: "mTLS"}, nil
+	}
+	if leaf.Subject.CommonName != "" {
+		return Principal{ID: leaf.Subject.CommonName, Scheme: "mTLS"}, nil
+	}
+	return Principal{}, errInvalidCredentials("")
+}
@@ -0,0 +1,51 @@
+package scim
+
+import (
+	"net/http"
+
+	"github.com/elimity-com/scim/auth"
+)
+
+// authenticate enforces s.Authenticator, if one is set, against the incoming request. It reports whether the
+// request may proceed; if it may, it returns the request with the resolved auth.Principal attached to its context
+// for ResourceHandler implementations to consume. If it may not, a 401 SCIM error with a WWW-Authenticate header
+// has already been written.
+//
+// If r's context already carries a Principal, authentication is skipped and r is returned unchanged. This lets
+// executeBulkOperation and searchAllHandler dispatch to handlers that call authenticate a second time, against a
+// synthetic in-process sub-request that structurally cannot carry the original request's TLS state, without
+// re-authenticating (and failing) an already-authenticated caller.
+func (s Server) authenticate(w http.ResponseWriter, r *http.Request) (*http.Request, bool) {
+	if s.Authenticator == nil {
+		return r, true
+	}
+	if _, ok := auth.FromContext(r.Context()); ok {
+		return r, true
+	}
+
+	principal, err := s.Authenticator.Authenticate(r)
+	if err != nil {
+		if challenge, ok := auth.Challenge(err); ok && challenge != "" {
+			w.Header().Set("WWW-Authenticate", challenge)
+		}
+		errorHandler(w, r, scimErrorUnauthorized)
+		return r, false
+	}
+
+	return r.WithContext(auth.WithPrincipal(r.Context(), principal)), true
+}
+
+// authenticationSchemes returns the ServiceProviderConfig.AuthenticationSchemes entry to advertise for s.
+// Authenticator, or nil if none is configured.
+func (s Server) authenticationSchemes() []AuthenticationScheme {
+	if s.Authenticator == nil {
+		return nil
+	}
+	return []AuthenticationScheme{{Type: s.Authenticator.Scheme()}}
+}
+
+// AuthenticationScheme describes one authentication scheme advertised in ServiceProviderConfig, as defined in
+// RFC 7644 §8.5.
+type AuthenticationScheme struct {
+	Type string `json:"type"`
+}
@@ -0,0 +1,228 @@
+package scim
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// bulkSchema is the schema URI a BulkRequest body must declare.
+const bulkSchema = "urn:ietf:params:scim:api:messages:2.0:BulkRequest"
+
+// bulkResponseSchema is the schema URI a BulkResponse body declares.
+const bulkResponseSchema = "urn:ietf:params:scim:api:messages:2.0:BulkResponse"
+
+// BulkRequest represents the body of a POST to the "/Bulk" endpoint, as defined in RFC 7644 §3.7.
+type BulkRequest struct {
+	Schemas      []string        `json:"schemas"`
+	FailOnErrors int             `json:"failOnErrors"`
+	Operations   []BulkOperation `json:"Operations"`
+}
+
+// BulkOperation is a single operation within a BulkRequest.
+type BulkOperation struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	BulkID string          `json:"bulkId"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// BulkResponse represents the body returned for a "/Bulk" request.
+type BulkResponse struct {
+	Schemas    []string                `json:"schemas"`
+	Operations []BulkOperationResponse `json:"Operations"`
+}
+
+// BulkOperationResponse is the per-operation result reported in a BulkResponse.
+type BulkOperationResponse struct {
+	Method   string      `json:"method"`
+	BulkID   string      `json:"bulkId,omitempty"`
+	Location string      `json:"location,omitempty"`
+	Status   string      `json:"status"`
+	Response interface{} `json:"response,omitempty"`
+}
+
+// bulkHandler receives an HTTP POST to the "/Bulk" endpoint and executes its operations in order, dispatching each
+// one to the matching resource handler via an internal in-process request rather than a network round-trip.
+func (s Server) bulkHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	maxPayloadSize := s.Config.Bulk.MaxPayloadSize
+
+	var body io.Reader = r.Body
+	if maxPayloadSize > 0 {
+		// Read one byte past the limit so an oversized body is rejected rather than silently truncated; r.Body is
+		// read through a LimitReader rather than trusting r.ContentLength, which is -1 for chunked request bodies.
+		body = io.LimitReader(r.Body, maxPayloadSize+1)
+	}
+	data, _ := ioutil.ReadAll(body)
+	if maxPayloadSize > 0 && int64(len(data)) > maxPayloadSize {
+		errorHandler(w, r, scimErrorBulkTooLarge)
+		return
+	}
+
+	var req BulkRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		errorHandler(w, r, scimErrorInvalidSyntax)
+		return
+	}
+
+	maxOperations := s.Config.Bulk.MaxOperations
+	if maxOperations > 0 && len(req.Operations) > maxOperations {
+		errorHandler(w, r, scimErrorBulkTooLarge)
+		return
+	}
+
+	resolver := newBulkIDResolver()
+	resp := BulkResponse{Schemas: []string{bulkResponseSchema}}
+
+	failures := 0
+	for _, op := range req.Operations {
+		if req.FailOnErrors > 0 && failures >= req.FailOnErrors {
+			break
+		}
+
+		opData, err := resolver.resolve(op.Data)
+		if err != nil {
+			resp.Operations = append(resp.Operations, BulkOperationResponse{
+				Method:   op.Method,
+				BulkID:   op.BulkID,
+				Status:   "400",
+				Response: scimErrorBulkIDCycle,
+			})
+			failures++
+			continue
+		}
+
+		opResp, status, location := s.executeBulkOperation(r, op.Method, op.Path, opData)
+		if op.BulkID != "" && status == http.StatusCreated {
+			if id, ok := extractID(opResp); ok {
+				resolver.bind(op.BulkID, id)
+			}
+		}
+		if status >= 300 {
+			failures++
+		}
+
+		resp.Operations = append(resp.Operations, BulkOperationResponse{
+			Method:   op.Method,
+			BulkID:   op.BulkID,
+			Location: location,
+			Status:   fmt.Sprintf("%d", status),
+			Response: opResp,
+		})
+	}
+
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		errorHandler(w, r, scimErrorInternalServer)
+		return
+	}
+	_, err = w.Write(raw)
+	if err != nil {
+		logger.Errorf("failed writing response: %v", err)
+	}
+}
+
+// executeBulkOperation dispatches a single bulk operation to the corresponding resource handler via an in-process
+// HTTP request, reusing the server's own mux so that routing, resource-type lookup and error handling stay
+// identical to a standalone request.
+func (s Server) executeBulkOperation(r *http.Request, method, path string, data []byte) (interface{}, int, string) {
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	req = req.WithContext(r.Context())
+	req.Header = r.Header.Clone()
+	rec := httptest.NewRecorder()
+
+	s.ServeHTTP(rec, req)
+
+	var body interface{}
+	_ = json.Unmarshal(rec.Body.Bytes(), &body)
+	return body, rec.Code, rec.Header().Get("Location")
+}
+
+// extractID pulls the "id" field out of a decoded resource response body.
+func extractID(resp interface{}) (string, bool) {
+	m, ok := resp.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	id, ok := m["id"].(string)
+	return id, ok
+}
+
+// bulkIDResolver resolves "bulkId:qwerty" references within later operations' data to the resource id produced by
+// an earlier operation in the same request, and rejects cyclical references.
+type bulkIDResolver struct {
+	resolved map[string]string
+}
+
+func newBulkIDResolver() *bulkIDResolver {
+	return &bulkIDResolver{resolved: map[string]string{}}
+}
+
+func (b *bulkIDResolver) bind(bulkID, resourceID string) {
+	b.resolved[bulkID] = resourceID
+}
+
+// resolve rewrites every "bulkId:<id>" reference found in data with the resolved resource id, returning an error if
+// a reference has not been resolved yet. Since operations execute strictly in order, this rejects every cyclical
+// reference, and also any reference to a later operation, to a failed one or to a bulkId no operation declares.
+func (b *bulkIDResolver) resolve(data json.RawMessage) ([]byte, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return data, nil
+	}
+
+	resolved, err := b.resolveValue(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(resolved)
+}
+
+func (b *bulkIDResolver) resolveValue(v interface{}) (interface{}, error) {
+	switch val := v.(type) {
+	case string:
+		if ref := strings.TrimPrefix(val, "bulkId:"); ref != val {
+			id, ok := b.resolved[ref]
+			if !ok {
+				return nil, fmt.Errorf("scim: unresolved or cyclical bulkId reference %q", ref)
+			}
+			return id, nil
+		}
+		return val, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, sub := range val {
+			resolvedSub, err := b.resolveValue(sub)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = resolvedSub
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, sub := range val {
+			resolvedSub, err := b.resolveValue(sub)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = resolvedSub
+		}
+		return out, nil
+	default:
+		return val, nil
+	}
+}
@@ -0,0 +1,78 @@
+package scim
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBulkIDResolver_Resolve(t *testing.T) {
+	resolver := newBulkIDResolver()
+	resolver.bind("qwerty", "1823g9")
+
+	data := json.RawMessage(`{"manager":"bulkId:qwerty","name":"bulkId:qwerty"}`)
+	raw, err := resolver.resolve(data)
+	if err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got["manager"] != "1823g9" || got["name"] != "1823g9" {
+		t.Errorf("resolve() = %v, want both fields resolved to 1823g9", got)
+	}
+}
+
+func TestBulkIDResolver_UnresolvedReferenceErrors(t *testing.T) {
+	resolver := newBulkIDResolver()
+
+	data := json.RawMessage(`{"manager":"bulkId:qwerty"}`)
+	if _, err := resolver.resolve(data); err == nil {
+		t.Fatal("resolve() error = nil, want error for unresolved bulkId reference")
+	}
+}
+
+func TestBulkIDResolver_ResolvesNestedValues(t *testing.T) {
+	resolver := newBulkIDResolver()
+	resolver.bind("a", "1")
+	resolver.bind("b", "2")
+
+	data := json.RawMessage(`{"members":["bulkId:a","bulkId:b"],"meta":{"manager":"bulkId:a"}}`)
+	raw, err := resolver.resolve(data)
+	if err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+
+	var got struct {
+		Members []string          `json:"members"`
+		Meta    map[string]string `json:"meta"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if len(got.Members) != 2 || got.Members[0] != "1" || got.Members[1] != "2" {
+		t.Errorf("got.Members = %v, want [1 2]", got.Members)
+	}
+	if got.Meta["manager"] != "1" {
+		t.Errorf("got.Meta[manager] = %q, want %q", got.Meta["manager"], "1")
+	}
+}
+
+func TestBulkIDResolver_NonReferenceStringsPassThrough(t *testing.T) {
+	resolver := newBulkIDResolver()
+
+	data := json.RawMessage(`{"userName":"bjensen"}`)
+	raw, err := resolver.resolve(data)
+	if err != nil {
+		t.Fatalf("resolve() error = %v", err)
+	}
+
+	var got map[string]string
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got["userName"] != "bjensen" {
+		t.Errorf("got[userName] = %q, want %q", got["userName"], "bjensen")
+	}
+}
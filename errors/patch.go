@@ -0,0 +1,23 @@
+package errors
+
+// PatchError is returned by Handler.Patch to indicate what went wrong while applying a PatchOp to a resource.
+type PatchError int
+
+const (
+	// PatchErrorNil indicates that the patch operations were applied successfully.
+	PatchErrorNil PatchError = iota
+	// PatchErrorNoPath indicates that a "remove" operation was received without a "path", which is required.
+	PatchErrorNoPath
+	// PatchErrorInvalidPath indicates that the "path" of an operation could not be resolved against the resource's schema.
+	PatchErrorInvalidPath
+	// PatchErrorInvalidOp indicates that the "op" of an operation was not one of "add", "remove" or "replace".
+	PatchErrorInvalidOp
+	// PatchErrorInvalidValue indicates that the "value" of an operation was not valid for the targeted attribute.
+	PatchErrorInvalidValue
+	// PatchErrorMutability indicates that the targeted attribute is not mutable, e.g. it is read-only or immutable.
+	PatchErrorMutability
+	// PatchErrorUniqueness indicates that applying the operation would violate a uniqueness constraint on the attribute.
+	PatchErrorUniqueness
+	// PatchErrorResourceNotFound indicates that no resource with the given identifier exists.
+	PatchErrorResourceNotFound
+)
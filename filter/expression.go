@@ -0,0 +1,73 @@
+// Package filter implements a parser for the SCIM filter grammar defined in RFC 7644 §3.4.2.2, producing an
+// abstract syntax tree that can be evaluated against a resource without re-parsing.
+package filter
+
+// Expression is implemented by every node of a parsed SCIM filter.
+type Expression interface {
+	expression()
+}
+
+// CompareOp is a SCIM filter comparison operator, as defined in RFC 7644 §3.4.2.2.
+type CompareOp string
+
+// The set of comparison operators supported by the SCIM filter grammar.
+const (
+	CompareOpEqual              CompareOp = "eq"
+	CompareOpNotEqual           CompareOp = "ne"
+	CompareOpContains           CompareOp = "co"
+	CompareOpStartsWith         CompareOp = "sw"
+	CompareOpEndsWith           CompareOp = "ew"
+	CompareOpGreaterThan        CompareOp = "gt"
+	CompareOpGreaterThanOrEqual CompareOp = "ge"
+	CompareOpLessThan           CompareOp = "lt"
+	CompareOpLessThanOrEqual    CompareOp = "le"
+	CompareOpPresent            CompareOp = "pr"
+)
+
+// LogicalOp is a SCIM filter logical operator combining two expressions.
+type LogicalOp string
+
+// The set of logical operators supported by the SCIM filter grammar.
+const (
+	LogicalOpAnd LogicalOp = "and"
+	LogicalOpOr  LogicalOp = "or"
+)
+
+// AttrExpr is a leaf expression comparing a resolved attribute path against a typed value, e.g. `userName eq
+// "bjensen"` or `meta.created ge "2011-05-13T04:42:34Z"`.
+type AttrExpr struct {
+	// AttributePath is the attribute path resolved against the resource type schema, e.g. []string{"name",
+	// "familyName"} for "name.familyName".
+	AttributePath []string
+	Op            CompareOp
+	// CompareValue holds the typed value to compare against: string, float64, bool or nil. It is nil when Op is
+	// CompareOpPresent.
+	CompareValue interface{}
+}
+
+func (AttrExpr) expression() {}
+
+// LogicalExpr combines two expressions with "and" or "or".
+type LogicalExpr struct {
+	Left, Right Expression
+	Op          LogicalOp
+}
+
+func (LogicalExpr) expression() {}
+
+// NotExpr negates an expression with "not".
+type NotExpr struct {
+	Expr Expression
+}
+
+func (NotExpr) expression() {}
+
+// ValuePath filters the elements of a multi-valued attribute by a sub-expression, e.g. `emails[type eq
+// "work"].value`. SubAttribute is empty unless a dotted sub-attribute follows the filter, e.g. "value" above.
+type ValuePath struct {
+	AttributePath []string
+	SubExpr       Expression
+	SubAttribute  string
+}
+
+func (ValuePath) expression() {}
@@ -0,0 +1,56 @@
+package filter
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	tokenString
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize splits a SCIM filter expression into identifiers (attribute names, operators, "and"/"or"/"not"),
+// quoted strings (with escapes resolved) and the punctuation "(", ")", "[", "]" and ".".
+func tokenize(s string) []token {
+	var tokens []token
+	runes := []rune(s)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(' || c == ')' || c == '[' || c == ']' || c == '.':
+			tokens = append(tokens, token{kind: tokenPunct, text: string(c)})
+			i++
+		case c == '"':
+			var b strings.Builder
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				b.WriteRune(runes[i])
+				i++
+			}
+			i++ // closing quote
+			tokens = append(tokens, token{kind: tokenString, text: b.String()})
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' &&
+				runes[i] != '[' && runes[i] != ']' && runes[i] != '.' && runes[i] != '"' {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: string(runes[start:i])})
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,67 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []token
+	}{
+		{
+			name: "simple comparison",
+			in:   `userName eq "bjensen"`,
+			want: []token{
+				{kind: tokenIdent, text: "userName"},
+				{kind: tokenIdent, text: "eq"},
+				{kind: tokenString, text: "bjensen"},
+			},
+		},
+		{
+			name: "dotted attribute path",
+			in:   `name.familyName eq "Jensen"`,
+			want: []token{
+				{kind: tokenIdent, text: "name"},
+				{kind: tokenPunct, text: "."},
+				{kind: tokenIdent, text: "familyName"},
+				{kind: tokenIdent, text: "eq"},
+				{kind: tokenString, text: "Jensen"},
+			},
+		},
+		{
+			name: "value path with bracketed sub-filter",
+			in:   `emails[type eq "work"].value`,
+			want: []token{
+				{kind: tokenIdent, text: "emails"},
+				{kind: tokenPunct, text: "["},
+				{kind: tokenIdent, text: "type"},
+				{kind: tokenIdent, text: "eq"},
+				{kind: tokenString, text: "work"},
+				{kind: tokenPunct, text: "]"},
+				{kind: tokenPunct, text: "."},
+				{kind: tokenIdent, text: "value"},
+			},
+		},
+		{
+			name: "escaped quote in string",
+			in:   `displayName eq "say \"hi\""`,
+			want: []token{
+				{kind: tokenIdent, text: "displayName"},
+				{kind: tokenIdent, text: "eq"},
+				{kind: tokenString, text: `say "hi"`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenize(tt.in)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
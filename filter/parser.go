@@ -0,0 +1,338 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Parse parses a SCIM filter expression, as defined in RFC 7644 §3.4.2.2, into an Expression tree. Every attribute
+// path referenced by the filter is resolved against resolver, and literal comparison values are coerced to the
+// resolved attribute's type; an unresolvable path or an incompatible value is a parse error.
+func Parse(filterText string, resolver SchemaResolver) (Expression, error) {
+	p := &parser{tokens: tokenize(filterText), resolver: resolver}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("filter: unexpected token %q", p.tokens[p.pos].text)
+	}
+	return expr, nil
+}
+
+// Match reports whether resource, as a generic JSON-decoded map, satisfies expr. It is a convenience helper for
+// Handler implementations that want to filter resources in memory rather than re-implement filter evaluation.
+func Match(resource map[string]interface{}, expr Expression) bool {
+	switch e := expr.(type) {
+	case AttrExpr:
+		return matchAttr(resource, e)
+	case NotExpr:
+		return !Match(resource, e.Expr)
+	case LogicalExpr:
+		if e.Op == LogicalOpAnd {
+			return Match(resource, e.Left) && Match(resource, e.Right)
+		}
+		return Match(resource, e.Left) || Match(resource, e.Right)
+	case ValuePath:
+		values, ok := lookup(resource, e.AttributePath)
+		if !ok {
+			return false
+		}
+		elems, ok := values.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, elem := range elems {
+			m, ok := elem.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if Match(m, e.SubExpr) {
+				return true
+			}
+		}
+		return false
+	default:
+		return false
+	}
+}
+
+func matchAttr(resource map[string]interface{}, e AttrExpr) bool {
+	value, ok := lookup(resource, e.AttributePath)
+	if e.Op == CompareOpPresent {
+		return ok && value != nil
+	}
+	if !ok {
+		return false
+	}
+
+	switch want := e.CompareValue.(type) {
+	case string:
+		got, ok := value.(string)
+		if !ok {
+			return false
+		}
+		switch e.Op {
+		case CompareOpEqual:
+			return strings.EqualFold(got, want)
+		case CompareOpNotEqual:
+			return !strings.EqualFold(got, want)
+		case CompareOpContains:
+			return strings.Contains(got, want)
+		case CompareOpStartsWith:
+			return strings.HasPrefix(got, want)
+		case CompareOpEndsWith:
+			return strings.HasSuffix(got, want)
+		case CompareOpGreaterThan:
+			return got > want
+		case CompareOpGreaterThanOrEqual:
+			return got >= want
+		case CompareOpLessThan:
+			return got < want
+		case CompareOpLessThanOrEqual:
+			return got <= want
+		}
+	case float64:
+		got, ok := value.(float64)
+		if !ok {
+			return false
+		}
+		switch e.Op {
+		case CompareOpEqual:
+			return got == want
+		case CompareOpNotEqual:
+			return got != want
+		case CompareOpGreaterThan:
+			return got > want
+		case CompareOpGreaterThanOrEqual:
+			return got >= want
+		case CompareOpLessThan:
+			return got < want
+		case CompareOpLessThanOrEqual:
+			return got <= want
+		}
+	case bool:
+		got, ok := value.(bool)
+		if !ok {
+			return false
+		}
+		if e.Op == CompareOpEqual {
+			return got == want
+		}
+		if e.Op == CompareOpNotEqual {
+			return got != want
+		}
+	}
+	return false
+}
+
+func lookup(resource map[string]interface{}, path []string) (interface{}, bool) {
+	var cur interface{} = resource
+	for _, segment := range path {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		cur, ok = m[segment]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// parser is a recursive-descent parser over the token stream produced by tokenize. The grammar, precedence
+// low-to-high, is: expr -> or ; or -> and ("or" and)* ; and -> unary ("and" unary)* ; unary -> "not" unary |
+// primary ; primary -> "(" or ")" | valuePath | attrExpr.
+type parser struct {
+	tokens   []token
+	pos      int
+	resolver SchemaResolver
+	// prefix is the attribute path of the enclosing value path, if any, e.g. []string{"emails"} while parsing the
+	// "type eq \"work\"" sub-filter of `emails[type eq "work"]`. It is prepended when resolving an attribute inside
+	// the brackets against resolver, so that "type" resolves as the "emails" sub-attribute it actually is rather
+	// than a top-level attribute.
+	prefix []string
+}
+
+func (p *parser) parseOr() (Expression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("or") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = LogicalExpr{Left: left, Right: right, Op: LogicalOpOr}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekKeyword("and") {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = LogicalExpr{Left: left, Right: right, Op: LogicalOpAnd}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expression, error) {
+	if p.peekKeyword("not") {
+		p.pos++
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return NotExpr{Expr: expr}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expression, error) {
+	if p.peekText("(") {
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekText(")") {
+			return nil, fmt.Errorf("filter: expected ')'")
+		}
+		p.pos++
+		return expr, nil
+	}
+	return p.parseAttrOrValuePath()
+}
+
+func (p *parser) parseAttrOrValuePath() (Expression, error) {
+	path, err := p.parseAttrPath()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peekText("[") {
+		p.pos++
+
+		outerPrefix := p.prefix
+		p.prefix = append(append([]string{}, outerPrefix...), path...)
+		sub, err := p.parseOr()
+		p.prefix = outerPrefix
+		if err != nil {
+			return nil, err
+		}
+		if !p.peekText("]") {
+			return nil, fmt.Errorf("filter: expected ']'")
+		}
+		p.pos++
+
+		var subAttr string
+		if p.peekText(".") {
+			p.pos++
+			rest, err := p.parseAttrPath()
+			if err != nil {
+				return nil, err
+			}
+			subAttr = strings.Join(rest, ".")
+		}
+		return ValuePath{AttributePath: path, SubExpr: sub, SubAttribute: subAttr}, nil
+	}
+
+	return p.parseAttrExpr(path)
+}
+
+func (p *parser) parseAttrExpr(path []string) (Expression, error) {
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("filter: expected operator after attribute path")
+	}
+	op := CompareOp(strings.ToLower(p.tokens[p.pos].text))
+	switch op {
+	case CompareOpEqual, CompareOpNotEqual, CompareOpContains, CompareOpStartsWith, CompareOpEndsWith,
+		CompareOpGreaterThan, CompareOpGreaterThanOrEqual, CompareOpLessThan, CompareOpLessThanOrEqual, CompareOpPresent:
+		p.pos++
+	default:
+		return nil, fmt.Errorf("filter: unknown operator %q", p.tokens[p.pos].text)
+	}
+
+	resolvePath := append(append([]string{}, p.prefix...), path...)
+	typ, _, ok := p.resolver.Resolve(resolvePath)
+	if !ok {
+		return nil, fmt.Errorf("filter: unknown attribute %q", strings.Join(resolvePath, "."))
+	}
+
+	if op == CompareOpPresent {
+		return AttrExpr{AttributePath: path, Op: op}, nil
+	}
+
+	if p.pos >= len(p.tokens) {
+		return nil, fmt.Errorf("filter: expected value after operator %q", op)
+	}
+	value, err := p.parseValue(typ)
+	if err != nil {
+		return nil, err
+	}
+	return AttrExpr{AttributePath: path, Op: op, CompareValue: value}, nil
+}
+
+func (p *parser) parseValue(typ AttributeType) (interface{}, error) {
+	tok := p.tokens[p.pos]
+	p.pos++
+
+	switch typ {
+	case AttributeTypeString, AttributeTypeDateTime, AttributeTypeBinary:
+		if tok.kind != tokenString {
+			return nil, fmt.Errorf("filter: expected quoted string, got %q", tok.text)
+		}
+		return tok.text, nil
+	case AttributeTypeBoolean:
+		b, err := strconv.ParseBool(tok.text)
+		if err != nil {
+			return nil, fmt.Errorf("filter: expected boolean, got %q", tok.text)
+		}
+		return b, nil
+	case AttributeTypeNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("filter: expected number, got %q", tok.text)
+		}
+		return f, nil
+	default:
+		return tok.text, nil
+	}
+}
+
+func (p *parser) parseAttrPath() ([]string, error) {
+	if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenIdent {
+		return nil, fmt.Errorf("filter: expected attribute name")
+	}
+	path := []string{p.tokens[p.pos].text}
+	p.pos++
+	for p.peekText(".") {
+		p.pos++
+		if p.pos >= len(p.tokens) || p.tokens[p.pos].kind != tokenIdent {
+			return nil, fmt.Errorf("filter: expected attribute name after '.'")
+		}
+		path = append(path, p.tokens[p.pos].text)
+		p.pos++
+	}
+	return path, nil
+}
+
+func (p *parser) peekText(text string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].text == text
+}
+
+func (p *parser) peekKeyword(kw string) bool {
+	return p.pos < len(p.tokens) && p.tokens[p.pos].kind == tokenIdent && strings.EqualFold(p.tokens[p.pos].text, kw)
+}
@@ -0,0 +1,132 @@
+package filter
+
+import (
+	"strings"
+	"testing"
+)
+
+// stubResolver resolves every attribute path present in types, case-insensitively like ResourceType.Resolve,
+// treating its top-level attribute as multi-valued when listed in multiValued.
+type stubResolver struct {
+	types       map[string]AttributeType
+	multiValued map[string]bool
+}
+
+func (r stubResolver) Resolve(path []string) (AttributeType, bool, bool) {
+	key := strings.ToLower(joinPath(path))
+	typ, ok := r.types[key]
+	if !ok {
+		return 0, false, false
+	}
+	return typ, r.multiValued[strings.ToLower(path[0])], true
+}
+
+func joinPath(path []string) string {
+	s := ""
+	for i, p := range path {
+		if i > 0 {
+			s += "."
+		}
+		s += p
+	}
+	return s
+}
+
+func newTestResolver() stubResolver {
+	return stubResolver{
+		types: map[string]AttributeType{
+			"username":     AttributeTypeString,
+			"active":       AttributeTypeBoolean,
+			"emails.value": AttributeTypeString,
+			"emails.type":  AttributeTypeString,
+		},
+		multiValued: map[string]bool{"emails": true},
+	}
+}
+
+func TestParse_AttrExpr(t *testing.T) {
+	expr, err := Parse(`userName eq "bjensen"`, newTestResolver())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	attr, ok := expr.(AttrExpr)
+	if !ok {
+		t.Fatalf("Parse() = %T, want AttrExpr", expr)
+	}
+	if attr.Op != CompareOpEqual || attr.CompareValue != "bjensen" {
+		t.Errorf("Parse() = %+v, want op eq value bjensen", attr)
+	}
+}
+
+func TestParse_UnknownAttribute(t *testing.T) {
+	if _, err := Parse(`nickname eq "bob"`, newTestResolver()); err == nil {
+		t.Fatal("Parse() error = nil, want error for unresolvable attribute")
+	}
+}
+
+// TestParse_ValuePathScopesSubFilter verifies that the sub-filter attribute of a value path, e.g. "type" in
+// `emails[type eq "work"]`, resolves as a sub-attribute of the outer attribute ("emails.type") rather than as a
+// top-level attribute in its own right.
+func TestParse_ValuePathScopesSubFilter(t *testing.T) {
+	resolver := newTestResolver()
+	// "type" alone is not in the resolver's table; only "emails.type" is. If the sub-filter is resolved without
+	// being scoped under "emails", parsing fails.
+	expr, err := Parse(`emails[type eq "work"]`, resolver)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	vp, ok := expr.(ValuePath)
+	if !ok {
+		t.Fatalf("Parse() = %T, want ValuePath", expr)
+	}
+	sub, ok := vp.SubExpr.(AttrExpr)
+	if !ok {
+		t.Fatalf("SubExpr = %T, want AttrExpr", vp.SubExpr)
+	}
+	// The AST still stores the sub-filter's attribute path relative to the value path's elements, since that is
+	// what Match evaluates it against.
+	if got := joinPath(sub.AttributePath); got != "type" {
+		t.Errorf("sub.AttributePath = %q, want %q", got, "type")
+	}
+}
+
+func TestParse_LogicalAndNot(t *testing.T) {
+	expr, err := Parse(`active eq true and not (userName eq "bjensen")`, newTestResolver())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	logical, ok := expr.(LogicalExpr)
+	if !ok {
+		t.Fatalf("Parse() = %T, want LogicalExpr", expr)
+	}
+	if logical.Op != LogicalOpAnd {
+		t.Errorf("logical.Op = %q, want %q", logical.Op, LogicalOpAnd)
+	}
+	if _, ok := logical.Right.(NotExpr); !ok {
+		t.Errorf("logical.Right = %T, want NotExpr", logical.Right)
+	}
+}
+
+func TestMatch_ValuePath(t *testing.T) {
+	expr, err := Parse(`emails[type eq "work"].value`, newTestResolver())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	resource := map[string]interface{}{
+		"emails": []interface{}{
+			map[string]interface{}{"type": "home", "value": "a@example.com"},
+			map[string]interface{}{"type": "work", "value": "b@example.com"},
+		},
+	}
+	if !Match(resource, expr) {
+		t.Error("Match() = false, want true")
+	}
+
+	resource["emails"] = []interface{}{
+		map[string]interface{}{"type": "home", "value": "a@example.com"},
+	}
+	if Match(resource, expr) {
+		t.Error("Match() = true, want false")
+	}
+}
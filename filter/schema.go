@@ -0,0 +1,24 @@
+package filter
+
+// AttributeType is the coerced Go type of a SCIM attribute's value, used to type-check and coerce comparison values
+// at parse time.
+type AttributeType int
+
+// The set of attribute types the parser coerces comparison values to.
+const (
+	AttributeTypeString AttributeType = iota
+	AttributeTypeBoolean
+	AttributeTypeNumber
+	AttributeTypeDateTime
+	AttributeTypeBinary
+	AttributeTypeComplex
+)
+
+// SchemaResolver resolves an attribute path, e.g. []string{"emails", "value"} for "emails.value", against a
+// resource type's schema. It is implemented by the caller so that this package does not need to depend on how
+// schemas are represented.
+type SchemaResolver interface {
+	// Resolve returns the type of the attribute at path and whether it is multi-valued. ok is false if no such
+	// attribute exists in the schema.
+	Resolve(path []string) (typ AttributeType, multiValued bool, ok bool)
+}
@@ -0,0 +1,13 @@
+package scim
+
+import "github.com/elimity-com/scim/filter"
+
+// Resolve implements filter.SchemaResolver for ResourceType, resolving an attribute path against the table of known
+// SCIM core attributes (a URN-qualified path is accepted as a string attribute), so that the filter package can type-check and coerce filter values
+// without needing to know how a resource type's full schema is represented.
+func (t ResourceType) Resolve(path []string) (typ filter.AttributeType, multiValued bool, ok bool) {
+	if len(path) > 0 && isQualifiedAttributePath(path[0]) {
+		return filter.AttributeTypeString, false, true
+	}
+	return resolveKnownAttribute(path)
+}
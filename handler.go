@@ -0,0 +1,17 @@
+package scim
+
+import "github.com/elimity-com/scim/errors"
+
+// Handler is implemented by the embedding application to back a resource type's CRUD and patch operations. Every
+// method signature mirrors the corresponding RFC 7644 operation; see ResourceHandler for the context-aware
+// counterpart new implementations should prefer.
+type Handler interface {
+	Create(attributes ResourceAttributes) (Resource, errors.PostError)
+	Get(id string) (Resource, errors.GetError)
+	GetAll(params ListRequestParams) (Page, errors.GetError)
+	Replace(id string, attributes ResourceAttributes) (Resource, errors.PutError)
+	Delete(id string) errors.DeleteError
+	// Patch applies operations, already parsed and validated against the resource type's schema, to the resource
+	// with the given id, as defined in RFC 7644 §3.5.2.
+	Patch(id string, operations []PatchOperation) (Resource, errors.PatchError)
+}
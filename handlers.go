@@ -9,10 +9,16 @@ import (
 	"github.com/elimity-com/scim/errors"
 )
 
+// errorHandler writes a scimError as the HTTP response. It is a package-level helper rather than a Server method so
+// that it can be called from contexts, such as precondition checks, that only have a ResponseWriter and Request at
+// hand; marshaling a scimError value cannot practically fail, but a failure here falls back to the standard logger
+// rather than crashing the process.
 func errorHandler(w http.ResponseWriter, r *http.Request, scimErr scimError) {
 	raw, err := json.Marshal(scimErr)
 	if err != nil {
-		log.Fatalf("failed marshaling scim error: %v", err)
+		log.Printf("failed marshaling scim error: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
 	w.WriteHeader(scimErr.status)
 	_, err = w.Write(raw)
@@ -24,6 +30,13 @@ func errorHandler(w http.ResponseWriter, r *http.Request, scimErr scimError) {
 // schemasHandler receives an HTTP GET to retrieve information about resource schemas supported by a SCIM service
 // provider. An HTTP GET to the endpoint "/Schemas" returns all supported schemas in ListResponse format.
 func (s Server) schemasHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
 	var schemas []interface{}
 	for _, v := range s.getSchemas() {
 		schemas = append(schemas, v)
@@ -31,17 +44,26 @@ func (s Server) schemasHandler(w http.ResponseWriter, r *http.Request) {
 
 	raw, err := json.Marshal(newListResponse(schemas))
 	if err != nil {
-		log.Fatalf("failed marshaling list response: %v", err)
+		logger.Errorf("failed marshaling list response: %v", err)
+		errorHandler(w, r, scimErrorInternalServer)
+		return
 	}
 	_, err = w.Write(raw)
 	if err != nil {
-		log.Printf("failed writing response: %v", err)
+		logger.Errorf("failed writing response: %v", err)
 	}
 }
 
 // schemaHandler receives an HTTP GET to retrieve individual schema definitions which can be returned by appending the
 // schema URI to the /Schemas endpoint. For example: "/Schemas/urn:ietf:params:scim:schemas:core:2.0:User"
 func (s Server) schemaHandler(w http.ResponseWriter, r *http.Request, id string) {
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
 	schema, ok := s.getSchemas()[id]
 	if !ok {
 		errorHandler(w, r, scimErrorResourceNotFound(id))
@@ -50,11 +72,13 @@ func (s Server) schemaHandler(w http.ResponseWriter, r *http.Request, id string)
 
 	raw, err := json.Marshal(schema)
 	if err != nil {
-		log.Fatalf("failed marshaling schema: %v", err)
+		logger.Errorf("failed marshaling schema: %v", err)
+		errorHandler(w, r, scimErrorInternalServer)
+		return
 	}
 	_, err = w.Write(raw)
 	if err != nil {
-		log.Printf("failed writing response: %v", err)
+		logger.Errorf("failed writing response: %v", err)
 	}
 }
 
@@ -62,6 +86,13 @@ func (s Server) schemaHandler(w http.ResponseWriter, r *http.Request, id string)
 // resources available on a SCIM service provider (e.g., Users and Groups).  Each resource type defines the endpoints,
 // the core schema URI that defines the resource, and any supported schema extensions.
 func (s Server) resourceTypesHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
 	var resourceTypes []interface{}
 	for _, v := range s.ResourceTypes {
 		resourceTypes = append(resourceTypes, v)
@@ -69,17 +100,26 @@ func (s Server) resourceTypesHandler(w http.ResponseWriter, r *http.Request) {
 
 	raw, err := json.Marshal(newListResponse(resourceTypes))
 	if err != nil {
-		log.Fatalf("failed marshaling list response: %v", err)
+		logger.Errorf("failed marshaling list response: %v", err)
+		errorHandler(w, r, scimErrorInternalServer)
+		return
 	}
 	_, err = w.Write(raw)
 	if err != nil {
-		log.Printf("failed writing response: %v", err)
+		logger.Errorf("failed writing response: %v", err)
 	}
 }
 
 // resourceTypeHandler receives an HTTP GET to retrieve individual resource types which can be returned by appending the
 // resource types name to the /ResourceTypes endpoint. For example: "/ResourceTypes/User"
 func (s Server) resourceTypeHandler(w http.ResponseWriter, r *http.Request, name string) {
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
 	var resourceType ResourceType
 	for _, r := range s.ResourceTypes {
 		if r.Name == name {
@@ -94,30 +134,52 @@ func (s Server) resourceTypeHandler(w http.ResponseWriter, r *http.Request, name
 
 	raw, err := json.Marshal(resourceType)
 	if err != nil {
-		log.Fatalf("failed marshaling resource type: %v", err)
+		logger.Errorf("failed marshaling resource type: %v", err)
+		errorHandler(w, r, scimErrorInternalServer)
+		return
 	}
 	_, err = w.Write(raw)
 	if err != nil {
-		log.Printf("failed writing response: %v", err)
+		logger.Errorf("failed writing response: %v", err)
 	}
 }
 
 // serviceProviderConfigHandler receives an HTTP GET to this endpoint will return a JSON structure that describes the
 // SCIM specification features available on a service provider.
 func (s Server) serviceProviderConfigHandler(w http.ResponseWriter, r *http.Request) {
-	raw, err := json.Marshal(s.Config)
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	config := s.Config
+	config.Versioning.Supported = s.supportsVersioning()
+	config.AuthenticationSchemes = s.authenticationSchemes()
+
+	raw, err := json.Marshal(config)
 	if err != nil {
-		log.Fatalf("failed marshaling service provider config: %v", err)
+		logger.Errorf("failed marshaling service provider config: %v", err)
+		errorHandler(w, r, scimErrorInternalServer)
+		return
 	}
 	_, err = w.Write(raw)
 	if err != nil {
-		log.Printf("failed writing response: %v", err)
+		logger.Errorf("failed writing response: %v", err)
 	}
 }
 
 // resourcePostHandler receives an HTTP POST request to the resource endpoint, such as "/Users" or "/Groups", as
 // defined by the associated resource type endpoint discovery to create new resources.
 func (s Server) resourcePostHandler(w http.ResponseWriter, r *http.Request, resourceType ResourceType) {
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
 	data, _ := ioutil.ReadAll(r.Body)
 
 	attributes, scimErr := resourceType.validate(data)
@@ -126,7 +188,11 @@ func (s Server) resourcePostHandler(w http.ResponseWriter, r *http.Request, reso
 		return
 	}
 
-	resource, postErr := resourceType.Handler.Create(attributes)
+	if !checkIfNoneMatch(w, r, resourceType, attributes) {
+		return
+	}
+
+	resource, postErr := resourceType.resourceHandler().Create(r.Context(), attributes)
 	if postErr != errors.PostErrorNil {
 		errorHandler(w, r, scimPostError(postErr))
 		return
@@ -134,19 +200,29 @@ func (s Server) resourcePostHandler(w http.ResponseWriter, r *http.Request, reso
 
 	raw, err := json.Marshal(resource.response(resourceType))
 	if err != nil {
-		log.Fatalf("failed marshaling resource: %v", err)
+		logger.Errorf("failed marshaling resource: %v", err)
+		errorHandler(w, r, scimErrorInternalServer)
+		return
 	}
+	w.Header().Set("ETag", weakETag(resourceVersion(resourceType, resource.ID, raw)))
 	w.WriteHeader(http.StatusCreated)
 	_, err = w.Write(raw)
 	if err != nil {
-		log.Printf("failed writing response: %v", err)
+		logger.Errorf("failed writing response: %v", err)
 	}
 }
 
 // resourceGetHandler receives an HTTP GET request to the resource endpoint, e.g., "/Users/{id}" or "/Groups/{id}",
 // where "{id}" is a resource identifier to retrieve a known resource.
 func (s Server) resourceGetHandler(w http.ResponseWriter, r *http.Request, id string, resourceType ResourceType) {
-	resource, getErr := resourceType.Handler.Get(id)
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	resource, getErr := resourceType.resourceHandler().Get(r.Context(), id)
 	if getErr != errors.GetErrorNil {
 		errorHandler(w, r, scimGetError(getErr, id))
 		return
@@ -154,21 +230,28 @@ func (s Server) resourceGetHandler(w http.ResponseWriter, r *http.Request, id st
 
 	raw, err := json.Marshal(resource.response(resourceType))
 	if err != nil {
+		logger.Errorf("failed marshaling resource: %v", err)
 		errorHandler(w, r, scimErrorInternalServer)
-		log.Fatalf("failed marshaling resource: %v", err)
 		return
 	}
+	w.Header().Set("ETag", weakETag(resourceVersion(resourceType, id, raw)))
 	_, err = w.Write(raw)
 	if err != nil {
-		log.Printf("failed writing response: %v", err)
+		logger.Errorf("failed writing response: %v", err)
 	}
 }
 
 // resourcesGetHandler receives an HTTP GET request to the resource endpoint, e.g., "/Users" or "/Groups", to retrieve
 // all known resources.
 func (s Server) resourcesGetHandler(w http.ResponseWriter, r *http.Request, resourceType ResourceType, params ListRequestParams) {
-	page, getError := resourceType.Handler.GetAll(params)
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
 
+	page, getError := resourceType.resourceHandler().GetAll(r.Context(), params)
 	if getError != errors.GetErrorNil {
 		errorHandler(w, r, scimGetAllError(getError))
 		return
@@ -177,21 +260,31 @@ func (s Server) resourcesGetHandler(w http.ResponseWriter, r *http.Request, reso
 	raw, err := json.Marshal(
 		page.toInternalListResponse(resourceType, params.StartIndex, params.Count),
 	)
-
 	if err != nil {
+		logger.Errorf("failed marshalling list response: %v", err)
 		errorHandler(w, r, scimErrorInternalServer)
-		log.Fatalf("failed marshalling list response: %v", err)
 		return
 	}
 	_, err = w.Write(raw)
 	if err != nil {
-		log.Printf("failed writing response: %v", err)
+		logger.Errorf("failed writing response: %v", err)
 	}
 }
 
 // resourcePutHandler receives an HTTP PUT to the resource endpoint, e.g., "/Users/{id}" or "/Groups/{id}", where
 // "{id}" is a resource identifier to replace a resource's attributes.
 func (s Server) resourcePutHandler(w http.ResponseWriter, r *http.Request, id string, resourceType ResourceType) {
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(w, r, resourceType, id) {
+		return
+	}
+
 	data, _ := ioutil.ReadAll(r.Body)
 
 	attributes, scimErr := resourceType.validate(data)
@@ -200,7 +293,7 @@ func (s Server) resourcePutHandler(w http.ResponseWriter, r *http.Request, id st
 		return
 	}
 
-	resource, putError := resourceType.Handler.Replace(id, attributes)
+	resource, putError := resourceType.resourceHandler().Replace(r.Context(), id, attributes)
 	if putError != errors.PutErrorNil {
 		errorHandler(w, r, scimPutError(putError, id))
 		return
@@ -208,18 +301,82 @@ func (s Server) resourcePutHandler(w http.ResponseWriter, r *http.Request, id st
 
 	raw, err := json.Marshal(resource.response(resourceType))
 	if err != nil {
-		log.Fatalf("failed marshaling resource: %v", err)
+		logger.Errorf("failed marshaling resource: %v", err)
+		errorHandler(w, r, scimErrorInternalServer)
+		return
 	}
+	w.Header().Set("ETag", weakETag(resourceVersion(resourceType, id, raw)))
 	_, err = w.Write(raw)
 	if err != nil {
-		log.Printf("failed writing response: %v", err)
+		logger.Errorf("failed writing response: %v", err)
+	}
+}
+
+// resourcePatchHandler receives an HTTP PATCH request to the resource endpoint, e.g., "/Users/{id}" or "/Groups/{id}",
+// where "{id}" is a resource identifier, to apply a set of partial modifications as described in RFC 7644 §3.5.2.
+func (s Server) resourcePatchHandler(w http.ResponseWriter, r *http.Request, id string, resourceType ResourceType) {
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(w, r, resourceType, id) {
+		return
+	}
+
+	data, _ := ioutil.ReadAll(r.Body)
+
+	var req patchRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		errorHandler(w, r, scimErrorInvalidSyntax)
+		return
+	}
+
+	operations, reqErr := resourceType.resolvePatchOperations(req.Operations)
+	if reqErr != nil {
+		errorHandler(w, r, reqErr.scimError())
+		return
+	}
+
+	resource, patchErr := resourceType.resourceHandler().Patch(r.Context(), id, operations)
+	if patchErr != errors.PatchErrorNil {
+		errorHandler(w, r, scimPatchError(patchErr, id))
+		return
+	}
+
+	if r.Header.Get("Prefer") == "return=minimal" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	raw, err := json.Marshal(resource.response(resourceType))
+	if err != nil {
+		logger.Errorf("failed marshaling resource: %v", err)
+		errorHandler(w, r, scimErrorInternalServer)
+		return
+	}
+	w.Header().Set("ETag", weakETag(resourceVersion(resourceType, id, raw)))
+	_, err = w.Write(raw)
+	if err != nil {
+		logger.Errorf("failed writing response: %v", err)
 	}
 }
 
 // resourceDeleteHandler receives an HTTP DELETE request to the resource endpoint, e.g., "/Users/{id}" or "/Groups/{id}",
 // where "{id}" is a resource identifier to delete a known resource.
 func (s Server) resourceDeleteHandler(w http.ResponseWriter, r *http.Request, id string, resourceType ResourceType) {
-	deleteErr := resourceType.Handler.Delete(id)
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	if !checkIfMatch(w, r, resourceType, id) {
+		return
+	}
+
+	deleteErr := resourceType.resourceHandler().Delete(r.Context(), id)
 	if deleteErr != errors.DeleteErrorNil {
 		errorHandler(w, r, scimDeleteError(deleteErr, id))
 		return
@@ -0,0 +1,67 @@
+package scim
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// Logger is the logging interface used by Server. Implementations can forward to whichever structured logging
+// library the embedding application already uses; With returns a Logger that includes the given key/value pairs on
+// every subsequent call.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	With(kv ...interface{}) Logger
+}
+
+// defaultLogger is the Logger used by a Server that does not set one explicitly. It forwards to the standard
+// library "log" package, prefixing every line with whatever fields have been attached via With.
+type defaultLogger struct {
+	fields []interface{}
+}
+
+// NewDefaultLogger returns the Logger a Server falls back to when none is configured.
+func NewDefaultLogger() Logger {
+	return defaultLogger{}
+}
+
+func (l defaultLogger) Debugf(format string, args ...interface{}) { l.logf("DEBUG", format, args...) }
+func (l defaultLogger) Infof(format string, args ...interface{})  { l.logf("INFO", format, args...) }
+func (l defaultLogger) Errorf(format string, args ...interface{}) { l.logf("ERROR", format, args...) }
+
+func (l defaultLogger) With(kv ...interface{}) Logger {
+	return defaultLogger{fields: append(append([]interface{}{}, l.fields...), kv...)}
+}
+
+func (l defaultLogger) logf(level, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if len(l.fields) > 0 {
+		log.Printf("%s %s %v", level, msg, l.fields)
+		return
+	}
+	log.Printf("%s %s", level, msg)
+}
+
+// logger returns s.Logger, falling back to NewDefaultLogger when unset.
+func (s Server) logger() Logger {
+	if s.Logger != nil {
+		return s.Logger
+	}
+	return NewDefaultLogger()
+}
+
+// requestIDHeader is the header clients can use to propagate their own correlation id for a request.
+const requestIDHeader = "X-Request-ID"
+
+// requestLogger returns a Logger tagged with the request's correlation id, taken from the X-Request-ID header if
+// present or generated otherwise, so that the log lines for a request can be correlated with each other. The id
+// is not written to the response.
+func (s Server) requestLogger(r *http.Request) Logger {
+	id := r.Header.Get(requestIDHeader)
+	if id == "" {
+		id = newRequestID()
+	}
+	return s.logger().With("request_id", id)
+}
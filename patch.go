@@ -0,0 +1,210 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/elimity-com/scim/errors"
+)
+
+// patchOpAdd, patchOpReplace and patchOpRemove are the three operation types defined by RFC 7644 §3.5.2.
+const (
+	patchOpAdd     = "add"
+	patchOpReplace = "replace"
+	patchOpRemove  = "remove"
+)
+
+// PatchOperation represents a single operation within a SCIM PatchOp request body, as defined in RFC 7644 §3.5.2.
+type PatchOperation struct {
+	// Op is one of "add", "replace" or "remove", case-insensitively.
+	Op string
+	// Path is the attribute path the operation applies to. It may be empty for "add" and "replace".
+	Path string
+	// Value is the value to add or replace. It is nil for "remove".
+	Value interface{}
+	// Parsed is the resolved form of Path, filled in by resolvePatchOperations before the operation ever reaches
+	// Handler.Patch. It is the zero ParsedPatchPath when Path is empty.
+	Parsed ParsedPatchPath
+}
+
+// patchRequest represents the body of a PATCH request as defined in RFC 7644 §3.5.2.
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations"`
+}
+
+// UnmarshalJSON unmarshals a single operation of a PatchOp request body into a PatchOperation.
+func (o *PatchOperation) UnmarshalJSON(data []byte) error {
+	var surface struct {
+		Op    string      `json:"op"`
+		Path  string      `json:"path"`
+		Value interface{} `json:"value"`
+	}
+	if err := json.Unmarshal(data, &surface); err != nil {
+		return err
+	}
+	o.Op = surface.Op
+	o.Path = surface.Path
+	o.Value = surface.Value
+	return nil
+}
+
+// ParsedPatchPath is the parsed form of a PatchOperation.Path, resolved against a resource type's schema by
+// resolvePatchOperations before the operation reaches Handler.Patch.
+type ParsedPatchPath struct {
+	// AttributeName is the top-level attribute the operation targets, e.g. "emails" or "name".
+	AttributeName string
+	// SubAttribute is set for dotted paths such as "name.familyName".
+	SubAttribute string
+	// ValueFilterAttr, ValueFilterOp and ValueFilterValue are set for value path expressions such as
+	// `emails[type eq "work"].value`, where ValueFilterAttr/Op/Value describe the "type eq \"work\"" selector and
+	// SubAttribute (if any) describes the ".value" suffix.
+	ValueFilterAttr  string
+	ValueFilterOp    string
+	ValueFilterValue string
+}
+
+var patchValueFilterRegexp = regexp.MustCompile(`^([a-zA-Z0-9:\.]+)\[([a-zA-Z0-9:]+)\s+(eq|ne|co|sw|ew|gt|ge|lt|le|pr)\s*(.*)\](?:\.(.+))?$`)
+
+// parsePatchPath parses a PatchOperation.Path into its component parts. It supports plain attribute names
+// ("displayName"), dotted sub-attribute paths ("name.familyName") and value path expressions with a filter selector
+// on a multi-valued attribute ("emails[type eq \"work\"].value").
+func parsePatchPath(path string) (ParsedPatchPath, error) {
+	if path == "" {
+		return ParsedPatchPath{}, fmt.Errorf("scim: empty patch path")
+	}
+
+	if matches := patchValueFilterRegexp.FindStringSubmatch(path); matches != nil {
+		return ParsedPatchPath{
+			AttributeName:    matches[1],
+			ValueFilterAttr:  matches[2],
+			ValueFilterOp:    matches[3],
+			ValueFilterValue: strings.Trim(matches[4], `"`),
+			SubAttribute:     matches[5],
+		}, nil
+	}
+
+	// A schema URN prefix, e.g. "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:", may itself contain
+	// a '.', so it stays part of AttributeName and only the remainder is split into attribute and sub-attribute.
+	var urn string
+	if i := strings.LastIndex(path, ":"); i >= 0 {
+		urn, path = path[:i+1], path[i+1:]
+	}
+	parts := strings.SplitN(path, ".", 2)
+	p := ParsedPatchPath{AttributeName: urn + parts[0]}
+	if len(parts) == 2 {
+		p.SubAttribute = parts[1]
+	}
+	return p, nil
+}
+
+// resolveAttributePath reports whether a dotted attribute path such as "name" or "name.familyName" is recognised
+// for t, using t's known schema attributes. A path qualified with a schema URN prefix, e.g. an enterprise extension
+// attribute, is always accepted since it does not appear in the known-attribute table.
+func (t ResourceType) resolveAttributePath(attributeName, subAttribute string) bool {
+	if isQualifiedAttributePath(attributeName) {
+		return true
+	}
+	path := []string{attributeName}
+	if subAttribute != "" {
+		path = append(path, strings.Split(subAttribute, ".")...)
+	}
+	_, _, ok := t.Resolve(path)
+	if !ok && subAttribute == "" {
+		// A complex or multi-valued attribute such as "name" or "emails" is known through its sub-attributes.
+		prefix := strings.ToLower(attributeName) + "."
+		for known := range knownAttributeTypes {
+			if strings.HasPrefix(known, prefix) {
+				return true
+			}
+		}
+	}
+	return ok
+}
+
+// resolvePatchOperations parses and validates every PatchOperation in a PatchOp request body against t's schema,
+// attaching the parsed path to each operation via PatchOperation.Parsed so that Handler.Patch implementations do
+// not need to re-parse it.
+func (t ResourceType) resolvePatchOperations(operations []PatchOperation) ([]PatchOperation, *patchRequestError) {
+	for i, op := range operations {
+		switch strings.ToLower(op.Op) {
+		case patchOpAdd, patchOpReplace:
+		case patchOpRemove:
+			if op.Path == "" {
+				return nil, &patchRequestError{op: errInvalidPatchOpNoPath}
+			}
+		default:
+			return nil, &patchRequestError{op: errInvalidPatchOpUnknown}
+		}
+
+		if op.Path == "" {
+			operations[i].Op = strings.ToLower(op.Op)
+			continue
+		}
+
+		parsed, err := parsePatchPath(op.Path)
+		if err != nil {
+			return nil, &patchRequestError{op: errInvalidPatchOpPath}
+		}
+
+		attr := parsed.AttributeName
+		if parsed.ValueFilterAttr != "" && !t.resolveAttributePath(attr, parsed.ValueFilterAttr) {
+			return nil, &patchRequestError{op: errInvalidPatchOpPath}
+		}
+		if !t.resolveAttributePath(attr, parsed.SubAttribute) {
+			return nil, &patchRequestError{op: errInvalidPatchOpPath}
+		}
+
+		operations[i].Op = strings.ToLower(op.Op)
+		operations[i].Parsed = parsed
+	}
+	return operations, nil
+}
+
+// patchRequestErrorKind enumerates why a PatchOp request body was rejected before it ever reached Handler.Patch.
+type patchRequestErrorKind int
+
+const (
+	errInvalidPatchOpNoPath patchRequestErrorKind = iota
+	errInvalidPatchOpUnknown
+	errInvalidPatchOpPath
+)
+
+// patchRequestError wraps a patchRequestErrorKind so resourcePatchHandler can map it onto the right SCIM error.
+type patchRequestError struct {
+	op patchRequestErrorKind
+}
+
+// scimError maps a patchRequestError onto the SCIM error returned to the client.
+func (e patchRequestError) scimError() scimError {
+	switch e.op {
+	case errInvalidPatchOpNoPath:
+		return scimErrorNoTarget
+	case errInvalidPatchOpPath:
+		return scimErrorInvalidPath
+	default:
+		return scimErrorInvalidSyntax
+	}
+}
+
+// scimPatchError maps the errors.PatchError returned by Handler.Patch onto the SCIM error returned to the client.
+func scimPatchError(patchErr errors.PatchError, id string) scimError {
+	switch patchErr {
+	case errors.PatchErrorResourceNotFound:
+		return scimErrorResourceNotFound(id)
+	case errors.PatchErrorNoPath:
+		return scimErrorNoTarget
+	case errors.PatchErrorInvalidPath:
+		return scimErrorInvalidPath
+	case errors.PatchErrorInvalidValue:
+		return scimErrorInvalidValue
+	case errors.PatchErrorMutability:
+		return scimErrorMutability
+	case errors.PatchErrorUniqueness:
+		return scimErrorUniqueness
+	default:
+		return scimErrorInternalServer
+	}
+}
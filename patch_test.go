@@ -0,0 +1,119 @@
+package scim
+
+import "testing"
+
+func TestParsePatchPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		want    ParsedPatchPath
+		wantErr bool
+	}{
+		{
+			name: "plain attribute",
+			path: "displayName",
+			want: ParsedPatchPath{AttributeName: "displayName"},
+		},
+		{
+			name: "dotted sub-attribute",
+			path: "name.familyName",
+			want: ParsedPatchPath{AttributeName: "name", SubAttribute: "familyName"},
+		},
+		{
+			name: "value filter expression",
+			path: `emails[type eq "work"].value`,
+			want: ParsedPatchPath{
+				AttributeName:    "emails",
+				ValueFilterAttr:  "type",
+				ValueFilterOp:    "eq",
+				ValueFilterValue: "work",
+				SubAttribute:     "value",
+			},
+		},
+		{
+			name: "value filter expression without sub-attribute",
+			path: `emails[type eq "work"]`,
+			want: ParsedPatchPath{
+				AttributeName:    "emails",
+				ValueFilterAttr:  "type",
+				ValueFilterOp:    "eq",
+				ValueFilterValue: "work",
+			},
+		},
+		{
+			name:    "empty path",
+			path:    "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePatchPath(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("parsePatchPath() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePatchPath() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("parsePatchPath(%q) = %+v, want %+v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResourceType_ResolvePatchOperations(t *testing.T) {
+	t.Run("rejects unknown attribute", func(t *testing.T) {
+		var rt ResourceType
+		_, reqErr := rt.resolvePatchOperations([]PatchOperation{{Op: "replace", Path: "notAnAttribute"}})
+		if reqErr == nil {
+			t.Fatal("resolvePatchOperations() error = nil, want invalid-path error")
+		}
+		if reqErr.op != errInvalidPatchOpPath {
+			t.Errorf("reqErr.op = %v, want errInvalidPatchOpPath", reqErr.op)
+		}
+	})
+
+	t.Run("accepts known attribute and attaches the parsed path", func(t *testing.T) {
+		var rt ResourceType
+		ops, reqErr := rt.resolvePatchOperations([]PatchOperation{{Op: "Replace", Path: "active", Value: true}})
+		if reqErr != nil {
+			t.Fatalf("resolvePatchOperations() error = %+v", reqErr)
+		}
+		if ops[0].Op != "replace" {
+			t.Errorf("ops[0].Op = %q, want %q", ops[0].Op, "replace")
+		}
+		if ops[0].Parsed.AttributeName != "active" {
+			t.Errorf("ops[0].Parsed.AttributeName = %q, want %q", ops[0].Parsed.AttributeName, "active")
+		}
+	})
+
+	t.Run("accepts a qualified extension attribute", func(t *testing.T) {
+		var rt ResourceType
+		const path = "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:employeeNumber"
+		_, reqErr := rt.resolvePatchOperations([]PatchOperation{{Op: "replace", Path: path}})
+		if reqErr != nil {
+			t.Fatalf("resolvePatchOperations() error = %+v", reqErr)
+		}
+	})
+
+	t.Run("remove without path is rejected", func(t *testing.T) {
+		var rt ResourceType
+		_, reqErr := rt.resolvePatchOperations([]PatchOperation{{Op: "remove"}})
+		if reqErr == nil || reqErr.op != errInvalidPatchOpNoPath {
+			t.Fatalf("resolvePatchOperations() error = %+v, want errInvalidPatchOpNoPath", reqErr)
+		}
+	})
+
+	t.Run("unknown op is rejected", func(t *testing.T) {
+		var rt ResourceType
+		_, reqErr := rt.resolvePatchOperations([]PatchOperation{{Op: "frobnicate", Path: "active"}})
+		if reqErr == nil || reqErr.op != errInvalidPatchOpUnknown {
+			t.Fatalf("resolvePatchOperations() error = %+v, want errInvalidPatchOpUnknown", reqErr)
+		}
+	})
+}
@@ -0,0 +1,15 @@
+package scim
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newRequestID generates a correlation id for a request that did not carry its own X-Request-ID header.
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,59 @@
+package scim
+
+import (
+	"context"
+
+	"github.com/elimity-com/scim/errors"
+)
+
+// ResourceHandler is the context-aware counterpart of Handler. Implementations receive the incoming request's
+// context as their first argument, so they can honour client cancellation and per-request deadlines the same way
+// the deadline/cancel plumbing works throughout the rest of the net/http stack.
+type ResourceHandler interface {
+	Create(ctx context.Context, attributes ResourceAttributes) (Resource, errors.PostError)
+	Get(ctx context.Context, id string) (Resource, errors.GetError)
+	GetAll(ctx context.Context, params ListRequestParams) (Page, errors.GetError)
+	Replace(ctx context.Context, id string, attributes ResourceAttributes) (Resource, errors.PutError)
+	Delete(ctx context.Context, id string) errors.DeleteError
+	Patch(ctx context.Context, id string, operations []PatchOperation) (Resource, errors.PatchError)
+}
+
+// legacyHandlerShim adapts a Handler, whose methods do not accept a context, to ResourceHandler by ignoring the
+// context on every call. It exists purely for backward compatibility, so that resource types configured with the
+// original Handler interface keep working unchanged.
+type legacyHandlerShim struct {
+	Handler
+}
+
+func (h legacyHandlerShim) Create(_ context.Context, attributes ResourceAttributes) (Resource, errors.PostError) {
+	return h.Handler.Create(attributes)
+}
+
+func (h legacyHandlerShim) Get(_ context.Context, id string) (Resource, errors.GetError) {
+	return h.Handler.Get(id)
+}
+
+func (h legacyHandlerShim) GetAll(_ context.Context, params ListRequestParams) (Page, errors.GetError) {
+	return h.Handler.GetAll(params)
+}
+
+func (h legacyHandlerShim) Replace(_ context.Context, id string, attributes ResourceAttributes) (Resource, errors.PutError) {
+	return h.Handler.Replace(id, attributes)
+}
+
+func (h legacyHandlerShim) Delete(_ context.Context, id string) errors.DeleteError {
+	return h.Handler.Delete(id)
+}
+
+func (h legacyHandlerShim) Patch(_ context.Context, id string, operations []PatchOperation) (Resource, errors.PatchError) {
+	return h.Handler.Patch(id, operations)
+}
+
+// resourceHandler returns resourceType's Handler as a ResourceHandler, using it directly when it already implements
+// the context-aware interface and falling back to the legacy, context-ignoring shim otherwise.
+func (t ResourceType) resourceHandler() ResourceHandler {
+	if v2, ok := t.Handler.(ResourceHandler); ok {
+		return v2
+	}
+	return legacyHandlerShim{Handler: t.Handler}
+}
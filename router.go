@@ -0,0 +1,121 @@
+package scim
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/elimity-com/scim/filter"
+)
+
+// ServeHTTP implements http.Handler for Server, dispatching requests across the SCIM endpoints defined by
+// RFC 7643 and RFC 7644 to the handler methods in this package.
+func (s Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch segments[0] {
+	case "Schemas":
+		if len(segments) == 1 {
+			s.schemasHandler(w, r)
+			return
+		}
+		s.schemaHandler(w, r, strings.Join(segments[1:], "/"))
+		return
+	case "ResourceTypes":
+		if len(segments) == 1 {
+			s.resourceTypesHandler(w, r)
+			return
+		}
+		s.resourceTypeHandler(w, r, segments[1])
+		return
+	case "ServiceProviderConfig":
+		s.serviceProviderConfigHandler(w, r)
+		return
+	case "Bulk":
+		if r.Method == http.MethodPost {
+			s.bulkHandler(w, r)
+			return
+		}
+	case ".search":
+		if r.Method == http.MethodPost {
+			s.searchAllHandler(w, r)
+			return
+		}
+	}
+
+	resourceType, ok := s.resourceTypeByEndpoint(segments[0])
+	if !ok {
+		errorHandler(w, r, scimErrorResourceNotFound(segments[0]))
+		return
+	}
+
+	if len(segments) == 2 && segments[1] == ".search" && r.Method == http.MethodPost {
+		s.searchHandler(w, r, resourceType)
+		return
+	}
+
+	switch {
+	case len(segments) == 1 && r.Method == http.MethodPost:
+		s.resourcePostHandler(w, r, resourceType)
+	case len(segments) == 1 && r.Method == http.MethodGet:
+		params, ok := parseListRequestParams(w, r, resourceType)
+		if !ok {
+			return
+		}
+		s.resourcesGetHandler(w, r, resourceType, params)
+	case len(segments) == 2 && r.Method == http.MethodGet:
+		s.resourceGetHandler(w, r, segments[1], resourceType)
+	case len(segments) == 2 && r.Method == http.MethodPut:
+		s.resourcePutHandler(w, r, segments[1], resourceType)
+	case len(segments) == 2 && r.Method == http.MethodPatch:
+		s.resourcePatchHandler(w, r, segments[1], resourceType)
+	case len(segments) == 2 && r.Method == http.MethodDelete:
+		s.resourceDeleteHandler(w, r, segments[1], resourceType)
+	default:
+		errorHandler(w, r, scimErrorResourceNotFound(strings.Join(segments, "/")))
+	}
+}
+
+// resourceTypeByEndpoint finds the ResourceType whose Endpoint matches the first path segment of a request, e.g.
+// "Users" for a request to "/Users" or "/Users/2819c223".
+func (s Server) resourceTypeByEndpoint(endpoint string) (ResourceType, bool) {
+	for _, resourceType := range s.ResourceTypes {
+		if strings.Trim(resourceType.Endpoint, "/") == endpoint {
+			return resourceType, true
+		}
+	}
+	return ResourceType{}, false
+}
+
+// parseListRequestParams builds a ListRequestParams from the query string of a GET request to a resource's
+// collection endpoint, as defined in RFC 7644 §3.4.2. It reports whether parsing succeeded; on failure, a SCIM error
+// response has already been written and the caller must not proceed.
+func parseListRequestParams(w http.ResponseWriter, r *http.Request, resourceType ResourceType) (ListRequestParams, bool) {
+	q := r.URL.Query()
+	params := ListRequestParams{
+		StartIndex: atoiOrZero(q.Get("startIndex")),
+		Count:      atoiOrZero(q.Get("count")),
+		SortBy:     q.Get("sortBy"),
+		SortOrder:  q.Get("sortOrder"),
+	}
+
+	if rawFilter := q.Get("filter"); rawFilter != "" {
+		expr, err := filter.Parse(rawFilter, resourceType)
+		if err != nil {
+			errorHandler(w, r, scimErrorInvalidFilter)
+			return ListRequestParams{}, false
+		}
+		params.Filter = expr
+	}
+
+	return params, true
+}
+
+// atoiOrZero parses s as an int, returning 0 if it is empty or not a valid integer.
+func atoiOrZero(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0
+	}
+	return n
+}
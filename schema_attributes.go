@@ -0,0 +1,85 @@
+package scim
+
+import (
+	"strings"
+
+	"github.com/elimity-com/scim/filter"
+)
+
+// knownAttributeTypes is a best-effort table of SCIM core User and Group attribute types, keyed by their
+// lowercased, dot-joined path (e.g. "emails.value"). Enterprise-user attributes are not listed; they are only
+// accepted when qualified with their schema URN (see isQualifiedAttributePath). ResourceType.Resolve and resolvePatchOperations both use it to
+// type-check filter and patch paths without needing a full schema representation.
+var knownAttributeTypes = map[string]filter.AttributeType{
+	"id":                      filter.AttributeTypeString,
+	"externalid":              filter.AttributeTypeString,
+	"username":                filter.AttributeTypeString,
+	"displayname":             filter.AttributeTypeString,
+	"nickname":                filter.AttributeTypeString,
+	"active":                  filter.AttributeTypeBoolean,
+	"name.formatted":          filter.AttributeTypeString,
+	"name.familyname":         filter.AttributeTypeString,
+	"name.givenname":          filter.AttributeTypeString,
+	"name.middlename":         filter.AttributeTypeString,
+	"name.honorificprefix":    filter.AttributeTypeString,
+	"name.honorificsuffix":    filter.AttributeTypeString,
+	"emails.value":            filter.AttributeTypeString,
+	"emails.type":             filter.AttributeTypeString,
+	"emails.primary":          filter.AttributeTypeBoolean,
+	"emails.display":          filter.AttributeTypeString,
+	"phonenumbers.value":      filter.AttributeTypeString,
+	"phonenumbers.type":       filter.AttributeTypeString,
+	"phonenumbers.primary":    filter.AttributeTypeBoolean,
+	"addresses.streetaddress": filter.AttributeTypeString,
+	"addresses.locality":      filter.AttributeTypeString,
+	"addresses.region":        filter.AttributeTypeString,
+	"addresses.postalcode":    filter.AttributeTypeString,
+	"addresses.country":       filter.AttributeTypeString,
+	"addresses.type":          filter.AttributeTypeString,
+	"groups.value":            filter.AttributeTypeString,
+	"groups.display":          filter.AttributeTypeString,
+	"members.value":           filter.AttributeTypeString,
+	"members.display":         filter.AttributeTypeString,
+	"meta.resourcetype":       filter.AttributeTypeString,
+	"meta.created":            filter.AttributeTypeDateTime,
+	"meta.lastmodified":       filter.AttributeTypeDateTime,
+	"meta.version":            filter.AttributeTypeString,
+	"meta.location":           filter.AttributeTypeString,
+}
+
+// multiValuedAttributes holds the top-level attribute names that are multi-valued, used by ResourceType.Resolve to
+// report AttrExpr.AttributePath as addressing an element of a list rather than a scalar.
+var multiValuedAttributes = map[string]bool{
+	"emails":           true,
+	"phonenumbers":     true,
+	"addresses":        true,
+	"groups":           true,
+	"members":          true,
+	"ims":              true,
+	"photos":           true,
+	"entitlements":     true,
+	"roles":            true,
+	"x509certificates": true,
+}
+
+// resolveKnownAttribute looks up path, e.g. []string{"emails", "value"}, case-insensitively against
+// knownAttributeTypes, reporting whether it is a recognised attribute and, if so, whether its top-level attribute is
+// multi-valued.
+func resolveKnownAttribute(path []string) (typ filter.AttributeType, multiValued bool, ok bool) {
+	if len(path) == 0 {
+		return 0, false, false
+	}
+	typ, ok = knownAttributeTypes[strings.ToLower(strings.Join(path, "."))]
+	if !ok {
+		return 0, false, false
+	}
+	return typ, multiValuedAttributes[strings.ToLower(path[0])], true
+}
+
+// isQualifiedAttributePath reports whether name is qualified with a schema URN prefix, e.g.
+// "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:employeeNumber". Such attributes belong to a schema
+// extension that resolveKnownAttribute does not have a fixed table for, so they are accepted without further
+// type-checking.
+func isQualifiedAttributePath(name string) bool {
+	return strings.Contains(name, ":")
+}
@@ -0,0 +1,9 @@
+package scim
+
+import "net/http"
+
+// The scimError values a /Bulk request can be rejected with.
+var (
+	scimErrorBulkTooLarge = scimError{status: http.StatusRequestEntityTooLarge}
+	scimErrorBulkIDCycle  = scimError{status: http.StatusBadRequest}
+)
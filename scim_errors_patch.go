@@ -0,0 +1,13 @@
+package scim
+
+import "net/http"
+
+// The scimError values a PatchOp request can be rejected with, mapped from errors.PatchError and
+// patchRequestErrorKind by scimPatchError and patchRequestError.scimError respectively.
+var (
+	scimErrorNoTarget     = scimError{status: http.StatusBadRequest}
+	scimErrorInvalidPath  = scimError{status: http.StatusBadRequest}
+	scimErrorInvalidValue = scimError{status: http.StatusBadRequest}
+	scimErrorMutability   = scimError{status: http.StatusBadRequest}
+	scimErrorUniqueness   = scimError{status: http.StatusConflict}
+)
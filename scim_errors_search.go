@@ -0,0 +1,7 @@
+package scim
+
+import "net/http"
+
+// scimErrorInvalidFilter is returned when a request's filter expression fails to parse, as defined in RFC 7644
+// §3.4.2.2.
+var scimErrorInvalidFilter = scimError{status: http.StatusBadRequest}
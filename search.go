@@ -0,0 +1,135 @@
+package scim
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/elimity-com/scim/errors"
+	"github.com/elimity-com/scim/filter"
+)
+
+// searchRequest represents the body of a POST to a ".search" endpoint, as defined in RFC 7644 §3.4.3.
+type searchRequest struct {
+	Schemas            []string `json:"schemas"`
+	Attributes         []string `json:"attributes"`
+	ExcludedAttributes []string `json:"excludedAttributes"`
+	Filter             string   `json:"filter"`
+	SortBy             string   `json:"sortBy"`
+	SortOrder          string   `json:"sortOrder"`
+	StartIndex         int      `json:"startIndex"`
+	Count              int      `json:"count"`
+}
+
+// searchHandler receives an HTTP POST to a ".search" endpoint, either "/.search" to search across every resource
+// type or "/{ResourceType}/.search" to search within a single resource type. Unlike a GET to the resource endpoint,
+// the filter (and the rest of the search parameters) is carried in the request body, which allows clients to send
+// filter expressions too long to fit in a query string.
+func (s Server) searchHandler(w http.ResponseWriter, r *http.Request, resourceType ResourceType) {
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	data, _ := ioutil.ReadAll(r.Body)
+
+	var req searchRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		errorHandler(w, r, scimErrorInvalidSyntax)
+		return
+	}
+
+	params := ListRequestParams{
+		StartIndex: req.StartIndex,
+		Count:      req.Count,
+		SortBy:     req.SortBy,
+		SortOrder:  req.SortOrder,
+	}
+
+	if req.Filter != "" {
+		expr, err := filter.Parse(req.Filter, resourceType)
+		if err != nil {
+			errorHandler(w, r, scimErrorInvalidFilter)
+			return
+		}
+		params.Filter = expr
+	}
+
+	page, getError := resourceType.resourceHandler().GetAll(r.Context(), params)
+	if getError != errors.GetErrorNil {
+		errorHandler(w, r, scimGetAllError(getError))
+		return
+	}
+
+	raw, err := json.Marshal(
+		page.toInternalListResponse(resourceType, params.StartIndex, params.Count),
+	)
+	if err != nil {
+		logger.Errorf("failed marshalling list response: %v", err)
+		errorHandler(w, r, scimErrorInternalServer)
+		return
+	}
+	_, err = w.Write(raw)
+	if err != nil {
+		logger.Errorf("failed writing response: %v", err)
+	}
+}
+
+// searchResponse is the subset of a searchHandler response body searchAllHandler needs in order to merge the
+// per-resource-type results of a top-level "/.search" into a single ListResponse.
+type searchResponse struct {
+	Resources []map[string]interface{} `json:"Resources"`
+}
+
+// searchAllHandler receives an HTTP POST to "/.search" and searches across every resource type, by delegating to
+// searchHandler for each one via an in-process request and merging the resulting ListResponses. A filter that does
+// not resolve against a given resource type's schema simply yields no results from that type, per RFC 7644 §3.4.3.
+func (s Server) searchAllHandler(w http.ResponseWriter, r *http.Request) {
+	logger := s.requestLogger(r)
+
+	r, ok := s.authenticate(w, r)
+	if !ok {
+		return
+	}
+
+	data, _ := ioutil.ReadAll(r.Body)
+
+	var merged searchResponse
+	for _, resourceType := range s.ResourceTypes {
+		req := httptest.NewRequest(http.MethodPost, "/"+resourceType.Endpoint+"/.search", bytes.NewReader(data))
+		req = req.WithContext(r.Context())
+		req.Header = r.Header.Clone()
+		rec := httptest.NewRecorder()
+
+		s.searchHandler(rec, req, resourceType)
+		if rec.Code != http.StatusOK {
+			continue
+		}
+
+		var resp searchResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			continue
+		}
+		merged.Resources = append(merged.Resources, resp.Resources...)
+	}
+
+	var resources []interface{}
+	for _, resource := range merged.Resources {
+		resources = append(resources, resource)
+	}
+
+	raw, err := json.Marshal(newListResponse(resources))
+	if err != nil {
+		logger.Errorf("failed marshalling list response: %v", err)
+		errorHandler(w, r, scimErrorInternalServer)
+		return
+	}
+	_, err = w.Write(raw)
+	if err != nil {
+		logger.Errorf("failed writing response: %v", err)
+	}
+}
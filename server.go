@@ -0,0 +1,52 @@
+package scim
+
+import "github.com/elimity-com/scim/auth"
+
+// Server implements http.Handler, serving the SCIM endpoints defined in RFC 7643 and RFC 7644 for the configured
+// resource types.
+type Server struct {
+	// ResourceTypes are the resource types the server exposes, each under its own Endpoint.
+	ResourceTypes []ResourceType
+	// Config is served as-is from the ServiceProviderConfig endpoint, except for the fields the server derives
+	// automatically from ResourceTypes and other configuration; see serviceProviderConfigHandler.
+	Config ServiceProviderConfig
+	// Logger receives structured log output from every handler. A Server that does not set it falls back to
+	// NewDefaultLogger; see logger().
+	Logger Logger
+	// Authenticator, if set, is enforced against every incoming request by authenticate.
+	Authenticator auth.Authenticator
+}
+
+// ServiceProviderConfig describes the SCIM features a Server supports, as returned from the ServiceProviderConfig
+// endpoint defined in RFC 7644 §4.
+type ServiceProviderConfig struct {
+	Bulk ServiceProviderConfigBulk `json:"bulk"`
+	// Versioning is filled in by serviceProviderConfigHandler from Server.supportsVersioning rather than configured
+	// by hand.
+	Versioning ServiceProviderConfigSupported `json:"versioning"`
+	// AuthenticationSchemes is filled in by serviceProviderConfigHandler from Server.authenticationSchemes rather
+	// than configured by hand.
+	AuthenticationSchemes []AuthenticationScheme `json:"authenticationSchemes"`
+}
+
+// ServiceProviderConfigSupported is the "{supported: bool}" shape used by several ServiceProviderConfig features.
+type ServiceProviderConfigSupported struct {
+	Supported bool `json:"supported"`
+}
+
+// ServiceProviderConfigBulk describes the bulk operation limits a Server enforces, as referenced by bulkHandler.
+type ServiceProviderConfigBulk struct {
+	// MaxOperations is the maximum number of operations a single BulkRequest may contain. Zero means unlimited.
+	MaxOperations int `json:"maxOperations"`
+	// MaxPayloadSize is the maximum size, in bytes, of a BulkRequest body. Zero means unlimited.
+	MaxPayloadSize int64 `json:"maxPayloadSize"`
+}
+
+// getSchemas returns the resource schemas the server exposes under the "/Schemas" endpoint, keyed by schema URI.
+func (s Server) getSchemas() map[string]interface{} {
+	schemas := make(map[string]interface{}, len(s.ResourceTypes))
+	for _, resourceType := range s.ResourceTypes {
+		schemas[resourceType.Name] = resourceType
+	}
+	return schemas
+}
@@ -0,0 +1,112 @@
+package scim
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/elimity-com/scim/errors"
+)
+
+// Versioner is an optional interface a Handler implementation can satisfy to supply a real, e.g. database-backed,
+// revision for a resource instead of the hash-based fallback computed from the resource's canonical JSON body.
+type Versioner interface {
+	// Version returns the current version of the resource with the given id, as a weak ETag value without the
+	// surrounding quotes, e.g. "3" or "a1b2c3".
+	Version(id string) (string, error)
+}
+
+// weakETag formats a version string as a weak ETag, e.g. `W/"a1b2c3"`.
+func weakETag(version string) string {
+	return fmt.Sprintf(`W/"%s"`, version)
+}
+
+// hashVersion computes the fallback version for a resource that does not come from a Versioner: the first 16 hex
+// digits of a SHA-256 hash over the canonical (marshaled) JSON body of the resource. Callers format it as a weak
+// ETag with weakETag.
+func hashVersion(raw []byte) string {
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// resourceVersion resolves the version of a resource, preferring a Versioner supplied by the resource type's
+// Handler and falling back to a hash of the resource's canonical JSON body.
+func resourceVersion(resourceType ResourceType, id string, raw []byte) string {
+	if versioner, ok := resourceType.Handler.(Versioner); ok {
+		if version, err := versioner.Version(id); err == nil {
+			return version
+		}
+	}
+	return hashVersion(raw)
+}
+
+// currentResourceVersion resolves the current version of an existing resource, preferring a Versioner and falling
+// back to fetching and hashing the resource itself.
+func currentResourceVersion(ctx context.Context, resourceType ResourceType, id string) (string, bool) {
+	if versioner, ok := resourceType.Handler.(Versioner); ok {
+		if version, err := versioner.Version(id); err == nil {
+			return version, true
+		}
+	}
+
+	resource, getErr := resourceType.resourceHandler().Get(ctx, id)
+	if getErr != errors.GetErrorNil {
+		return "", false
+	}
+	raw, err := json.Marshal(resource.response(resourceType))
+	if err != nil {
+		return "", false
+	}
+	return hashVersion(raw), true
+}
+
+// checkIfMatch enforces an If-Match precondition against a resource's current version, as defined in RFC 7644
+// §3.14. It reports whether the request may proceed; if not, it has already written a 412 response.
+func checkIfMatch(w http.ResponseWriter, r *http.Request, resourceType ResourceType, id string) bool {
+	ifMatch := r.Header.Get("If-Match")
+	if ifMatch == "" {
+		return true
+	}
+	if ifMatch == "*" {
+		return true
+	}
+	if version, ok := currentResourceVersion(r.Context(), resourceType, id); ok && ifMatch == weakETag(version) {
+		return true
+	}
+	errorHandler(w, r, scimErrorPreconditionFailed)
+	return false
+}
+
+// supportsVersioning reports whether any of the server's resource types has a Handler that satisfies Versioner, so
+// that ServiceProviderConfig.Versioning can be advertised automatically rather than configured by hand.
+func (s Server) supportsVersioning() bool {
+	for _, resourceType := range s.ResourceTypes {
+		if _, ok := resourceType.Handler.(Versioner); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkIfNoneMatch enforces an "If-None-Match: *" precondition, as used by POST-with-known-id flows to require that
+// no resource with the client-supplied id already exists. It reports whether the request may proceed. A plain POST
+// that leaves the server to assign the id has nothing to conflict with, so it always proceeds.
+func checkIfNoneMatch(w http.ResponseWriter, r *http.Request, resourceType ResourceType, attributes ResourceAttributes) bool {
+	if r.Header.Get("If-None-Match") != "*" {
+		return true
+	}
+
+	id, ok := attributes["id"].(string)
+	if !ok || id == "" {
+		return true
+	}
+
+	if _, getErr := resourceType.resourceHandler().Get(r.Context(), id); getErr == errors.GetErrorNil {
+		errorHandler(w, r, scimErrorPreconditionFailed)
+		return false
+	}
+	return true
+}